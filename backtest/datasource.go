@@ -0,0 +1,98 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	ib "github.com/benalexau/go.trade"
+)
+
+// Tick is a single historical market data point for one contract. It mirrors
+// the fields an Engine would otherwise receive asynchronously as
+// TickPrice/TickSize replies.
+type Tick struct {
+	Contract ib.Contract
+	Time     time.Time
+	Price    float64
+	Size     int64
+}
+
+// DataSource yields historical Ticks in chronological order. Implementations
+// need not be safe for concurrent use; the backtest Engine drives a single
+// DataSource from one goroutine.
+type DataSource interface {
+	// Next returns the next Tick in the replay, or io.EOF once exhausted.
+	Next() (Tick, error)
+}
+
+// CSVDataSource reads Ticks from a CSV file with the columns
+// symbol,secType,exchange,currency,time,price,size. Time is parsed with
+// time.RFC3339.
+type CSVDataSource struct {
+	reader *csv.Reader
+	closer io.Closer
+}
+
+// NewCSVDataSource opens path and returns a DataSource reading Ticks from it.
+func NewCSVDataSource(path string) (*CSVDataSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CSVDataSource{reader: csv.NewReader(f), closer: f}, nil
+}
+
+func (s *CSVDataSource) Next() (Tick, error) {
+	record, err := s.reader.Read()
+	if err != nil {
+		return Tick{}, err
+	}
+
+	if len(record) < 7 {
+		return Tick{}, fmt.Errorf("backtest: row has %d columns, want 7 (symbol,secType,exchange,currency,time,price,size): %v", len(record), record)
+	}
+
+	t, err := time.Parse(time.RFC3339, record[4])
+	if err != nil {
+		return Tick{}, fmt.Errorf("backtest: bad time %q: %s", record[4], err)
+	}
+
+	price, err := strconv.ParseFloat(record[5], 64)
+	if err != nil {
+		return Tick{}, fmt.Errorf("backtest: bad price %q: %s", record[5], err)
+	}
+
+	size, err := strconv.ParseInt(record[6], 10, 64)
+	if err != nil {
+		return Tick{}, fmt.Errorf("backtest: bad size %q: %s", record[6], err)
+	}
+
+	return Tick{
+		Contract: ib.Contract{
+			Symbol:       record[0],
+			SecurityType: record[1],
+			Exchange:     record[2],
+			Currency:     record[3],
+		},
+		Time:  t,
+		Price: price,
+		Size:  size,
+	}, nil
+}
+
+// Close releases any resources held open by the underlying file.
+func (s *CSVDataSource) Close() error {
+	return s.closer.Close()
+}
+
+// NewParquetDataSource always returns an error and never opens path: this
+// module has no parquet decoder, so parquet files cannot be replayed. Use
+// NewCSVDataSource for file-fed backtests.
+func NewParquetDataSource(path string) (DataSource, error) {
+	return nil, fmt.Errorf("backtest: parquet data sources are not yet implemented (%s)", path)
+}
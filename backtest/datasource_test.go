@@ -0,0 +1,108 @@
+package backtest
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeCSV(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ticks.csv")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("cannot write fixture: %s", err)
+	}
+	return path
+}
+
+func TestCSVDataSourceReadsRows(t *testing.T) {
+	path := writeCSV(t, "AUD,CASH,IDEALPRO,USD,2020-01-01T00:00:00Z,0.75,20000\n"+
+		"AUD,CASH,IDEALPRO,USD,2020-01-01T00:00:01Z,0.76,10000\n")
+
+	s, err := NewCSVDataSource(path)
+	if err != nil {
+		t.Fatalf("cannot open data source: %s", err)
+	}
+	defer s.Close()
+
+	tick, err := s.Next()
+	if err != nil {
+		t.Fatalf("cannot read first tick: %s", err)
+	}
+	if tick.Contract.Symbol != "AUD" || tick.Contract.SecurityType != "CASH" || tick.Contract.Exchange != "IDEALPRO" || tick.Contract.Currency != "USD" {
+		t.Fatalf("unexpected contract: %+v", tick.Contract)
+	}
+	if tick.Price != 0.75 || tick.Size != 20000 {
+		t.Fatalf("unexpected tick: %+v", tick)
+	}
+	if !tick.Time.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected time: %v", tick.Time)
+	}
+
+	if _, err := s.Next(); err != nil {
+		t.Fatalf("cannot read second tick: %s", err)
+	}
+
+	if _, err := s.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestCSVDataSourceRejectsShortRow(t *testing.T) {
+	path := writeCSV(t, "AUD,CASH,IDEALPRO,USD,2020-01-01T00:00:00Z,0.75\n")
+
+	s, err := NewCSVDataSource(path)
+	if err != nil {
+		t.Fatalf("cannot open data source: %s", err)
+	}
+	defer s.Close()
+
+	if _, err := s.Next(); err == nil {
+		t.Fatal("expected an error for a row missing the size column")
+	}
+}
+
+func TestCSVDataSourceRejectsBadTime(t *testing.T) {
+	path := writeCSV(t, "AUD,CASH,IDEALPRO,USD,not-a-time,0.75,20000\n")
+
+	s, err := NewCSVDataSource(path)
+	if err != nil {
+		t.Fatalf("cannot open data source: %s", err)
+	}
+	defer s.Close()
+
+	if _, err := s.Next(); err == nil {
+		t.Fatal("expected an error for an unparseable time")
+	}
+}
+
+func TestCSVDataSourceRejectsBadPrice(t *testing.T) {
+	path := writeCSV(t, "AUD,CASH,IDEALPRO,USD,2020-01-01T00:00:00Z,not-a-price,20000\n")
+
+	s, err := NewCSVDataSource(path)
+	if err != nil {
+		t.Fatalf("cannot open data source: %s", err)
+	}
+	defer s.Close()
+
+	if _, err := s.Next(); err == nil {
+		t.Fatal("expected an error for an unparseable price")
+	}
+}
+
+func TestCSVDataSourceRejectsBadSize(t *testing.T) {
+	path := writeCSV(t, "AUD,CASH,IDEALPRO,USD,2020-01-01T00:00:00Z,0.75,not-a-size\n")
+
+	s, err := NewCSVDataSource(path)
+	if err != nil {
+		t.Fatalf("cannot open data source: %s", err)
+	}
+	defer s.Close()
+
+	if _, err := s.Next(); err == nil {
+		t.Fatal("expected an error for an unparseable size")
+	}
+}
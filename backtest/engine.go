@@ -0,0 +1,200 @@
+package backtest
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	ib "github.com/benalexau/go.trade"
+)
+
+// InitialAccount seeds the simulated account the backtest Engine reports
+// against.
+type InitialAccount struct {
+	AccountCode string
+	Cash        float64
+}
+
+// Engine replays historical Ticks from a DataSource instead of talking to
+// TWS, simulating fills and delivering the same OrderStatus/ExecutionData/
+// CommissionReport replies a live trade.Engine would. It implements
+// NextRequestId/Subscribe/Unsubscribe/Send, so code written directly against
+// those four methods runs unchanged against either Engine.
+//
+// ExecutionManager and InstrumentManager are constructed against a concrete
+// *trade.Engine rather than an interface, so neither can be pointed at a
+// backtest Engine no matter which methods it implements; doing so would mean
+// introducing an Engine interface and changing every constructor and helper
+// in the trade package that currently takes *trade.Engine, which is out of
+// scope here. Strategies written against Subscribe/Send (or NewSimplePriceMatching
+// directly) can already run offline and deterministically against this
+// Engine; only the ExecutionManager/InstrumentManager convenience wrappers
+// cannot.
+type Engine struct {
+	source  DataSource
+	start   time.Time
+	end     time.Time
+	account InitialAccount
+
+	lock        sync.Mutex
+	nextReqId   int64
+	matchers    map[string]*SimplePriceMatching
+	subscribers map[int64][]chan ib.Reply
+	execId      int64
+}
+
+// NewBacktestEngine returns an Engine that replays every Tick from source
+// falling within [start, end], simulating fills against account.
+func NewBacktestEngine(source DataSource, start, end time.Time, account InitialAccount) *Engine {
+	return &Engine{
+		source:      source,
+		start:       start,
+		end:         end,
+		account:     account,
+		matchers:    map[string]*SimplePriceMatching{},
+		subscribers: map[int64][]chan ib.Reply{},
+	}
+}
+
+// NextRequestId returns a new, unique request id, mirroring trade.Engine.
+func (e *Engine) NextRequestId() int64 {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.nextReqId++
+	return e.nextReqId
+}
+
+// Subscribe registers ch to receive replies matching id.
+func (e *Engine) Subscribe(ch chan ib.Reply, id int64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.subscribers[id] = append(e.subscribers[id], ch)
+}
+
+// Unsubscribe removes a previously registered channel.
+func (e *Engine) Unsubscribe(ch chan ib.Reply, id int64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	chans := e.subscribers[id]
+	for i, c := range chans {
+		if c == ch {
+			e.subscribers[id] = append(chans[:i], chans[i+1:]...)
+			break
+		}
+	}
+}
+
+// Send accepts a Request, simulating order placement/cancellation against
+// the matching engine for its contract.
+func (e *Engine) Send(r ib.Request) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	switch req := r.(type) {
+	case *ib.PlaceOrder:
+		m := e.matcherFor(req.Contract)
+		m.Add(req)
+	case *ib.CancelOrder:
+		for _, m := range e.matchers {
+			m.Cancel(req.OrderId)
+		}
+	}
+
+	return nil
+}
+
+func (e *Engine) matcherFor(c ib.Contract) *SimplePriceMatching {
+	key := c.Symbol + ":" + c.SecurityType + ":" + c.Exchange + ":" + c.Currency
+	m, ok := e.matchers[key]
+	if !ok {
+		m = NewSimplePriceMatching(c)
+		e.matchers[key] = m
+	}
+	return m
+}
+
+// Run replays every Tick in [start, end], matching working orders against
+// each Tick and delivering the resulting fill replies to subscribers. It
+// delivers no market data (tick price or size) replies. Run returns when
+// source is exhausted.
+func (e *Engine) Run() error {
+	for {
+		tick, err := e.source.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if tick.Time.Before(e.start) || tick.Time.After(e.end) {
+			continue
+		}
+
+		e.lock.Lock()
+		m := e.matcherFor(tick.Contract)
+		fills := m.Match(tick)
+		e.lock.Unlock()
+
+		for _, f := range fills {
+			e.publishFill(tick, f)
+		}
+	}
+}
+
+// publishFill emits the synthetic OrderStatus, ExecutionData and
+// CommissionReport replies (and the corresponding Execution) for a fill
+// produced by the matching engine.
+func (e *Engine) publishFill(tick Tick, f fill) {
+	e.lock.Lock()
+	e.execId++
+	execId := fmt.Sprintf("backtest.%d.%d", f.orderId, e.execId)
+	e.lock.Unlock()
+
+	status := "Filled"
+	if !f.done {
+		status = "PartiallyFilled"
+	}
+
+	exec := ib.Execution{
+		OrderId:      f.orderId,
+		ExecId:       execId,
+		Time:         tick.Time,
+		AccountCode:  e.account.AccountCode,
+		Exchange:     tick.Contract.Exchange,
+		Shares:       f.shares,
+		Price:        f.price,
+		CumQty:       f.cumQty,
+		AveragePrice: f.averagePrice,
+	}
+
+	e.deliver(f.orderId, &ib.OrderStatus{
+		Id:            f.orderId,
+		Status:        status,
+		Filled:        f.cumQty,
+		AvgFillPrice:  f.averagePrice,
+		LastFillPrice: f.price,
+	})
+	e.deliver(f.orderId, &ib.ExecutionData{
+		Id:        f.orderId,
+		Contract:  tick.Contract,
+		Execution: exec,
+	})
+	e.deliver(f.orderId, &ib.CommissionReport{
+		ExecId: execId,
+	})
+}
+
+func (e *Engine) deliver(id int64, r ib.Reply) {
+	e.lock.Lock()
+	chans := append([]chan ib.Reply{}, e.subscribers[id]...)
+	e.lock.Unlock()
+
+	for _, ch := range chans {
+		ch <- r
+	}
+}
@@ -0,0 +1,148 @@
+package backtest
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	ib "github.com/benalexau/go.trade"
+)
+
+// sliceDataSource is a DataSource over a fixed, in-memory slice of Ticks,
+// for tests that do not want to round-trip through CSVDataSource.
+type sliceDataSource struct {
+	ticks []Tick
+	pos   int
+}
+
+func (s *sliceDataSource) Next() (Tick, error) {
+	if s.pos >= len(s.ticks) {
+		return Tick{}, io.EOF
+	}
+	tick := s.ticks[s.pos]
+	s.pos++
+	return tick, nil
+}
+
+func TestEngineRunPublishesFills(t *testing.T) {
+	contract := ib.Contract{Symbol: "AUD", SecurityType: "CASH", Exchange: "IDEALPRO", Currency: "USD"}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	source := &sliceDataSource{ticks: []Tick{
+		{Contract: contract, Time: start, Price: 0.75, Size: 100},
+	}}
+
+	e := NewBacktestEngine(source, start, end, InitialAccount{AccountCode: "DU123", Cash: 10000})
+
+	id := e.NextRequestId()
+	ch := make(chan ib.Reply, 3)
+	e.Subscribe(ch, id)
+	defer e.Unsubscribe(ch, id)
+
+	req := &ib.PlaceOrder{OrderId: id, Contract: contract, Action: "BUY", OrderType: "MKT", TotalQty: 100}
+	if err := e.Send(req); err != nil {
+		t.Fatalf("cannot send order: %s", err)
+	}
+
+	if err := e.Run(); err != nil {
+		t.Fatalf("run failed: %s", err)
+	}
+
+	var gotStatus, gotExecution, gotCommission bool
+	for i := 0; i < 3; i++ {
+		select {
+		case r := <-ch:
+			switch v := r.(type) {
+			case *ib.OrderStatus:
+				gotStatus = true
+				if v.Status != "Filled" || v.Filled != 100 {
+					t.Fatalf("unexpected order status: %+v", v)
+				}
+			case *ib.ExecutionData:
+				gotExecution = true
+				if v.Execution.AccountCode != "DU123" || v.Execution.CumQty != 100 {
+					t.Fatalf("unexpected execution: %+v", v.Execution)
+				}
+			case *ib.CommissionReport:
+				gotCommission = true
+			default:
+				t.Fatalf("unexpected reply type %T", r)
+			}
+		default:
+			t.Fatalf("expected 3 replies, only received %d", i)
+		}
+	}
+
+	if !gotStatus || !gotExecution || !gotCommission {
+		t.Fatalf("missing replies: status=%v execution=%v commission=%v", gotStatus, gotExecution, gotCommission)
+	}
+}
+
+func TestEngineRunSkipsTicksOutsideWindow(t *testing.T) {
+	contract := ib.Contract{Symbol: "AUD", SecurityType: "CASH", Exchange: "IDEALPRO", Currency: "USD"}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	source := &sliceDataSource{ticks: []Tick{
+		{Contract: contract, Time: start.Add(-time.Minute), Price: 0.75, Size: 100},
+		{Contract: contract, Time: end.Add(time.Minute), Price: 0.76, Size: 100},
+	}}
+
+	e := NewBacktestEngine(source, start, end, InitialAccount{})
+
+	id := e.NextRequestId()
+	ch := make(chan ib.Reply, 1)
+	e.Subscribe(ch, id)
+	defer e.Unsubscribe(ch, id)
+
+	req := &ib.PlaceOrder{OrderId: id, Contract: contract, Action: "BUY", OrderType: "MKT", TotalQty: 100}
+	if err := e.Send(req); err != nil {
+		t.Fatalf("cannot send order: %s", err)
+	}
+
+	if err := e.Run(); err != nil {
+		t.Fatalf("run failed: %s", err)
+	}
+
+	select {
+	case r := <-ch:
+		t.Fatalf("expected no fills for ticks outside [start, end], got %v", r)
+	default:
+	}
+}
+
+func TestEngineSendCancelOrder(t *testing.T) {
+	contract := ib.Contract{Symbol: "AUD", SecurityType: "CASH", Exchange: "IDEALPRO", Currency: "USD"}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	source := &sliceDataSource{ticks: []Tick{
+		{Contract: contract, Time: start, Price: 0.75, Size: 100},
+	}}
+
+	e := NewBacktestEngine(source, start, end, InitialAccount{})
+
+	id := e.NextRequestId()
+	ch := make(chan ib.Reply, 1)
+	e.Subscribe(ch, id)
+	defer e.Unsubscribe(ch, id)
+
+	req := &ib.PlaceOrder{OrderId: id, Contract: contract, Action: "BUY", OrderType: "MKT", TotalQty: 100}
+	if err := e.Send(req); err != nil {
+		t.Fatalf("cannot send order: %s", err)
+	}
+	if err := e.Send(&ib.CancelOrder{OrderId: id}); err != nil {
+		t.Fatalf("cannot send cancel: %s", err)
+	}
+
+	if err := e.Run(); err != nil {
+		t.Fatalf("run failed: %s", err)
+	}
+
+	select {
+	case r := <-ch:
+		t.Fatalf("expected no fills for a cancelled order, got %v", r)
+	default:
+	}
+}
@@ -0,0 +1,138 @@
+package backtest
+
+import (
+	ib "github.com/benalexau/go.trade"
+)
+
+// order is the matching engine's view of a working PlaceOrder request.
+type order struct {
+	request  *ib.PlaceOrder
+	filled   int64
+	avgPrice float64
+}
+
+// SimplePriceMatching is a per-contract order matching model driven by a
+// stream of historical Ticks. Market orders fill against every Tick; limit
+// orders fill whenever a Tick crosses the limit price. Either kind fills at
+// most the Tick's Size (when positive) per Tick, so an order larger than
+// that completes over several partial fills.
+type SimplePriceMatching struct {
+	contract ib.Contract
+	working  map[int64]*order
+	order    []int64 // orderIds in the order Add was called, for deterministic Match
+}
+
+// NewSimplePriceMatching returns a matching engine for a single contract.
+func NewSimplePriceMatching(contract ib.Contract) *SimplePriceMatching {
+	return &SimplePriceMatching{
+		contract: contract,
+		working:  map[int64]*order{},
+	}
+}
+
+// Add begins working an order against subsequent Ticks.
+func (m *SimplePriceMatching) Add(req *ib.PlaceOrder) {
+	m.working[req.OrderId] = &order{request: req}
+	m.order = append(m.order, req.OrderId)
+}
+
+// Cancel stops working an order, if still open.
+func (m *SimplePriceMatching) Cancel(orderId int64) {
+	delete(m.working, orderId)
+	m.remove(orderId)
+}
+
+// remove drops orderId from m.order. It does not touch m.working.
+func (m *SimplePriceMatching) remove(orderId int64) {
+	for i, id := range m.order {
+		if id == orderId {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// fill describes a (possibly partial) execution produced by matching a Tick
+// against the working book.
+type fill struct {
+	orderId      int64
+	shares       int64
+	price        float64
+	cumQty       int64
+	averagePrice float64
+	done         bool
+}
+
+// Match applies a Tick to the working book and returns the fills it
+// produced, in the order the orders were added (ie a stable, deterministic
+// order across runs, rather than Go's randomized map iteration order).
+func (m *SimplePriceMatching) Match(tick Tick) []fill {
+	var fills []fill
+	var done []int64
+
+	for _, id := range m.order {
+		o, ok := m.working[id]
+		if !ok {
+			continue // cancelled since being added to m.order
+		}
+
+		if crosses(o.request, tick.Price) {
+			remaining := o.request.TotalQty - o.filled
+			qty := remaining
+			if tick.Size > 0 && tick.Size < qty {
+				qty = tick.Size
+			}
+
+			o.avgPrice = weightedAverage(o.avgPrice, o.filled, tick.Price, qty)
+			o.filled += qty
+
+			isDone := o.filled >= o.request.TotalQty
+			fills = append(fills, fill{
+				orderId:      id,
+				shares:       qty,
+				price:        tick.Price,
+				cumQty:       o.filled,
+				averagePrice: o.avgPrice,
+				done:         isDone,
+			})
+
+			if isDone {
+				delete(m.working, id)
+				done = append(done, id)
+			}
+		}
+	}
+
+	for _, id := range done {
+		m.remove(id)
+	}
+
+	return fills
+}
+
+// crosses reports whether price would trigger req, given its order type and
+// limit price. Market orders always cross; limit orders cross only when the
+// tick price is at or better than the limit.
+func crosses(req *ib.PlaceOrder, price float64) bool {
+	switch req.OrderType {
+	case "MKT":
+		return true
+	case "LMT":
+		if req.Action == "BUY" {
+			return price <= req.LimitPrice
+		}
+		return price >= req.LimitPrice
+	default:
+		return false
+	}
+}
+
+// weightedAverage folds a new fill of qty shares at price into the running
+// average price of a position that already holds filled shares at avg.
+func weightedAverage(avg float64, filled int64, price float64, qty int64) float64 {
+	if filled+qty == 0 {
+		return 0
+	}
+
+	return (avg*float64(filled) + price*float64(qty)) / float64(filled+qty)
+}
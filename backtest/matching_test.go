@@ -0,0 +1,122 @@
+package backtest
+
+import (
+	"testing"
+
+	ib "github.com/benalexau/go.trade"
+)
+
+func TestSimplePriceMatchingFillOrder(t *testing.T) {
+	contract := ib.Contract{Symbol: "AUD", SecurityType: "CASH", Exchange: "IDEALPRO"}
+	m := NewSimplePriceMatching(contract)
+
+	for orderId := int64(1); orderId <= 5; orderId++ {
+		m.Add(&ib.PlaceOrder{OrderId: orderId, Contract: contract, Action: "BUY", OrderType: "MKT", TotalQty: 100})
+	}
+
+	for i := 0; i < 3; i++ {
+		fills := m.Match(Tick{Contract: contract, Price: 0.75, Size: 20})
+
+		if len(fills) != 5 {
+			t.Fatalf("run %d: expected 5 fills, got %d", i, len(fills))
+		}
+		for j, id := range []int64{1, 2, 3, 4, 5} {
+			if fills[j].orderId != id {
+				t.Fatalf("run %d: expected fill order %v, got order id %d at position %d", i, []int64{1, 2, 3, 4, 5}, fills[j].orderId, j)
+			}
+		}
+	}
+}
+
+// TestSimplePriceMatchingPartialFillProgression checks a market order that
+// only partially crosses against each Tick's Size reports a realistic
+// CumQty/AveragePrice progression, finishing "done" only once fully filled.
+func TestSimplePriceMatchingPartialFillProgression(t *testing.T) {
+	contract := ib.Contract{Symbol: "AUD", SecurityType: "CASH", Exchange: "IDEALPRO"}
+	m := NewSimplePriceMatching(contract)
+	m.Add(&ib.PlaceOrder{OrderId: 1, Contract: contract, Action: "BUY", OrderType: "MKT", TotalQty: 100})
+
+	fills := m.Match(Tick{Contract: contract, Price: 0.70, Size: 40})
+	if len(fills) != 1 {
+		t.Fatalf("expected 1 fill, got %d", len(fills))
+	}
+	f := fills[0]
+	if f.cumQty != 40 || f.averagePrice != 0.70 || f.done {
+		t.Fatalf("unexpected first fill: %+v", f)
+	}
+
+	fills = m.Match(Tick{Contract: contract, Price: 0.80, Size: 40})
+	if len(fills) != 1 {
+		t.Fatalf("expected 1 fill, got %d", len(fills))
+	}
+	f = fills[0]
+	wantAvg := (0.70*40 + 0.80*40) / 80
+	if f.cumQty != 80 || f.averagePrice != wantAvg || f.done {
+		t.Fatalf("unexpected second fill: %+v, want avg %v", f, wantAvg)
+	}
+
+	// Only 20 remain: a Tick with more Size than that must not overfill.
+	fills = m.Match(Tick{Contract: contract, Price: 0.90, Size: 40})
+	if len(fills) != 1 {
+		t.Fatalf("expected 1 fill, got %d", len(fills))
+	}
+	f = fills[0]
+	wantAvg = (0.70*40 + 0.80*40 + 0.90*20) / 100
+	if f.shares != 20 || f.cumQty != 100 || f.averagePrice != wantAvg || !f.done {
+		t.Fatalf("unexpected final fill: %+v, want avg %v", f, wantAvg)
+	}
+
+	// The order is no longer working once done.
+	if fills := m.Match(Tick{Contract: contract, Price: 0.90, Size: 40}); len(fills) != 0 {
+		t.Fatalf("expected no further fills for a completed order, got %v", fills)
+	}
+}
+
+// TestSimplePriceMatchingLimitCrossing checks LMT orders only fill once the
+// Tick price crosses the limit in the direction favourable to Action, and
+// that a BUY and a SELL limit resting at the same price react to opposite
+// sides of a Tick.
+func TestSimplePriceMatchingLimitCrossing(t *testing.T) {
+	contract := ib.Contract{Symbol: "AUD", SecurityType: "CASH", Exchange: "IDEALPRO"}
+	m := NewSimplePriceMatching(contract)
+	m.Add(&ib.PlaceOrder{OrderId: 1, Contract: contract, Action: "BUY", OrderType: "LMT", LimitPrice: 0.75, TotalQty: 100})
+	m.Add(&ib.PlaceOrder{OrderId: 2, Contract: contract, Action: "SELL", OrderType: "LMT", LimitPrice: 0.85, TotalQty: 100})
+
+	// Between the two limits: neither crosses.
+	if fills := m.Match(Tick{Contract: contract, Price: 0.80, Size: 100}); len(fills) != 0 {
+		t.Fatalf("expected no fills at 0.80, got %v", fills)
+	}
+
+	// At or below the BUY limit: only the BUY crosses.
+	fills := m.Match(Tick{Contract: contract, Price: 0.70, Size: 100})
+	if len(fills) != 1 || fills[0].orderId != 1 {
+		t.Fatalf("expected only the BUY limit to cross at 0.70, got %v", fills)
+	}
+
+	// At or above the SELL limit: only the SELL crosses.
+	fills = m.Match(Tick{Contract: contract, Price: 0.90, Size: 100})
+	if len(fills) != 1 || fills[0].orderId != 2 {
+		t.Fatalf("expected only the SELL limit to cross at 0.90, got %v", fills)
+	}
+}
+
+// TestSimplePriceMatchingCancel checks a cancelled order stops working and
+// leaves no trace in subsequent Match calls.
+func TestSimplePriceMatchingCancel(t *testing.T) {
+	contract := ib.Contract{Symbol: "AUD", SecurityType: "CASH", Exchange: "IDEALPRO"}
+	m := NewSimplePriceMatching(contract)
+	m.Add(&ib.PlaceOrder{OrderId: 1, Contract: contract, Action: "BUY", OrderType: "MKT", TotalQty: 100})
+	m.Add(&ib.PlaceOrder{OrderId: 2, Contract: contract, Action: "BUY", OrderType: "MKT", TotalQty: 100})
+
+	m.Cancel(1)
+
+	fills := m.Match(Tick{Contract: contract, Price: 0.75, Size: 100})
+	if len(fills) != 1 || fills[0].orderId != 2 {
+		t.Fatalf("expected only order 2 to fill after cancelling order 1, got %v", fills)
+	}
+
+	// Cancelling an order that was never added, or was already removed,
+	// must not panic.
+	m.Cancel(1)
+	m.Cancel(99)
+}
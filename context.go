@@ -0,0 +1,83 @@
+package trade
+
+import (
+	"context"
+	"errors"
+)
+
+// Send sends r. It is a thin wrapper over SendContext using
+// context.Background(), so every caller - including code written before
+// SendContext existed - gets the same cancellation and orderstore-hook
+// behaviour.
+func (e *Engine) Send(r Request) error {
+	return e.SendContext(context.Background(), r)
+}
+
+// SendContext sends r, cancelling the pending request (as CancelOrder or
+// CancelMarketData would) once ctx is done.
+func (e *Engine) SendContext(ctx context.Context, r Request) error {
+	if err := e.send(r); err != nil {
+		return err
+	}
+
+	notifyStoreOfSend(e, r)
+
+	if ctx.Done() != nil {
+		go func() {
+			<-ctx.Done()
+			if cancelled, ok := r.(cancellableRequest); ok {
+				e.Send(cancelled.Cancel())
+			}
+		}()
+	}
+
+	return nil
+}
+
+// cancellableRequest is implemented by Requests that have a corresponding
+// cancel message (eg RequestMarketData / CancelMarketData).
+type cancellableRequest interface {
+	Cancel() Request
+}
+
+// Subscribe registers ch to receive replies for id. It is a thin wrapper
+// over SubscribeContext using context.Background().
+func (e *Engine) Subscribe(ch chan Reply, id int64) {
+	e.SubscribeContext(context.Background(), ch, id)
+}
+
+// SubscribeContext registers ch to receive replies for id, automatically
+// calling Unsubscribe once ctx is done.
+func (e *Engine) SubscribeContext(ctx context.Context, ch chan Reply, id int64) {
+	e.subscribe(ch, id)
+
+	if ctx.Done() != nil {
+		go func() {
+			<-ctx.Done()
+			e.Unsubscribe(ch, id)
+		}()
+	}
+}
+
+// Expect blocks until ch delivers a Reply whose IncomingMessageId is one of
+// ids, ctx is done, or ch is closed. It does not watch the Engine's state, so
+// a fatal Engine error does not end the wait. It replaces the
+// select/timeout loop every test previously open-coded by hand.
+func (e *Engine) Expect(ctx context.Context, ch chan Reply, ids ...IncomingMessageId) (Reply, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case r, ok := <-ch:
+			if !ok {
+				return nil, errors.New("trade: channel closed while waiting for reply")
+			}
+
+			for _, id := range ids {
+				if r.code() == id {
+					return r, nil
+				}
+			}
+		}
+	}
+}
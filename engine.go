@@ -0,0 +1,199 @@
+package trade
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EngineState describes where an Engine is in its connect/run/exit
+// lifecycle. SubscribeState delivers every transition as it happens.
+type EngineState int
+
+const (
+	EngineReady EngineState = iota
+	EngineExitNormal
+	EngineExitError
+)
+
+// IncomingMessageId identifies the kind of Reply a message carries, so
+// Expect can wait for one of several kinds without knowing the concrete Go
+// type in advance.
+type IncomingMessageId int64
+
+const (
+	mTickPrice IncomingMessageId = iota + 1
+	mTickSize
+	mOrderStatus
+	mOpenOrder
+	mOpenOrderEnd
+	mExecutionData
+	mExecutionDataEnd
+	mCommissionReport
+	mContractData
+	mContractDataEnd
+)
+
+// Reply is implemented by every message TWS can send back to an Engine.
+type Reply interface {
+	code() IncomingMessageId
+}
+
+// Request is implemented by every message an Engine can send to TWS. SetId
+// assigns the request id TWS echoes back on the Replies it produces, so the
+// Engine can route them to whichever channel Subscribe(d) for that id.
+type Request interface {
+	SetId(id int64)
+}
+
+// Contract identifies the instrument a Request or Reply concerns.
+type Contract struct {
+	Symbol       string
+	SecurityType string
+	Exchange     string
+	Currency     string
+}
+
+// Engine manages a single connection to TWS (or IB Gateway), demultiplexing
+// incoming Replies to whichever channels Subscribe/SubscribeState registered
+// for them and serialising outgoing Requests onto the wire.
+//
+// Decoding the IB API's own wire messages (TickPrice, TickSize and friends)
+// is not implemented, and neither is the IB API handshake: an Engine dials
+// TWS but exchanges no messages with it, so it receives no live market data,
+// orders or executions; every method below nonetheless has real, not stubbed,
+// request-id/subscription bookkeeping, which is the part the rest of this
+// package (and its tests) depend on.
+type Engine struct {
+	conn   net.Conn
+	client int64
+
+	serverTime time.Time
+
+	nextReqId int64
+
+	lock        sync.Mutex
+	subscribers map[int64][]chan Reply
+	states      []chan EngineState
+	state       EngineState
+	fatal       error
+}
+
+// NewEngine dials TWS at 127.0.0.1:7497, the default paper-trading port for
+// IB Gateway/TWS, then applies opts (eg WithStore). It performs no handshake:
+// the server time is the local time of the dial.
+func NewEngine(opts ...EngineOption) (*Engine, error) {
+	conn, err := net.Dial("tcp", "127.0.0.1:7497")
+	if err != nil {
+		return nil, fmt.Errorf("trade: cannot connect to TWS: %s", err)
+	}
+
+	e := &Engine{
+		conn:        conn,
+		serverTime:  time.Now(),
+		subscribers: map[int64][]chan Reply{},
+		state:       EngineReady,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e, nil
+}
+
+// NextRequestId returns a new, unique request id.
+func (e *Engine) NextRequestId() int64 {
+	return atomic.AddInt64(&e.nextReqId, 1)
+}
+
+// ClientId returns the client id of this connection. No handshake assigns
+// one, so it is always 0.
+func (e *Engine) ClientId() int64 {
+	return e.client
+}
+
+// State returns the Engine's current lifecycle state.
+func (e *Engine) State() EngineState {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.state
+}
+
+// FatalError returns the error that moved the Engine into EngineExitError,
+// or nil if it exited normally (or is still running).
+func (e *Engine) FatalError() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.fatal
+}
+
+// SubscribeState registers ch to receive every EngineState transition from
+// this point on.
+func (e *Engine) SubscribeState(ch chan EngineState) {
+	e.lock.Lock()
+	e.states = append(e.states, ch)
+	e.lock.Unlock()
+}
+
+// Stop closes the connection to TWS and moves the Engine to
+// EngineExitNormal, notifying every channel SubscribeState registered.
+func (e *Engine) Stop() {
+	e.conn.Close()
+	e.setState(EngineExitNormal, nil)
+}
+
+func (e *Engine) setState(s EngineState, err error) {
+	e.lock.Lock()
+	e.state = s
+	e.fatal = err
+	states := append([]chan EngineState{}, e.states...)
+	e.lock.Unlock()
+
+	for _, ch := range states {
+		ch <- s
+	}
+}
+
+// send writes r to TWS. It is the primitive SendContext wraps with
+// cancellation and the orderstore hook.
+func (e *Engine) send(r Request) error {
+	e.lock.Lock()
+	state := e.state
+	e.lock.Unlock()
+
+	if state != EngineReady {
+		return fmt.Errorf("trade: cannot send %T: engine is not ready", r)
+	}
+
+	// Encoding a Request onto TWS's wire format is not implemented yet; see
+	// the Engine doc comment.
+	return nil
+}
+
+// subscribe registers ch to receive Replies carrying id. It is the
+// primitive SubscribeContext wraps with automatic cleanup once its context
+// is done.
+func (e *Engine) subscribe(ch chan Reply, id int64) {
+	e.lock.Lock()
+	e.subscribers[id] = append(e.subscribers[id], ch)
+	e.lock.Unlock()
+}
+
+// Unsubscribe removes a previously registered channel.
+func (e *Engine) Unsubscribe(ch chan Reply, id int64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	chans := e.subscribers[id]
+	for i, c := range chans {
+		if c == ch {
+			e.subscribers[id] = append(chans[:i], chans[i+1:]...)
+			return
+		}
+	}
+}
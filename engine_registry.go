@@ -0,0 +1,42 @@
+package trade
+
+import "sync"
+
+// engineRegistry associates a value of type T with an Engine for as long as
+// that Engine exists. It exists because Engine does not carry fields for
+// per-feature state that other files in this package need to attach to it
+// (eg a pubsub.Bus, an orderstore.Store) - those files have no access to
+// Engine's declaration to add one. Callers are responsible for calling
+// delete once the Engine they registered against reaches a terminal
+// EngineState, typically from a goroutine started by SubscribeState.
+type engineRegistry[T any] struct {
+	lock   sync.Mutex
+	values map[*Engine]T
+}
+
+func newEngineRegistry[T any]() *engineRegistry[T] {
+	return &engineRegistry[T]{values: map[*Engine]T{}}
+}
+
+// get returns the value registered for e, if any.
+func (r *engineRegistry[T]) get(e *Engine) (T, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	v, ok := r.values[e]
+	return v, ok
+}
+
+// set registers v for e, replacing any existing value.
+func (r *engineRegistry[T]) set(e *Engine, v T) {
+	r.lock.Lock()
+	r.values[e] = v
+	r.lock.Unlock()
+}
+
+// delete removes the value registered for e, if any.
+func (r *engineRegistry[T]) delete(e *Engine) {
+	r.lock.Lock()
+	delete(r.values, e)
+	r.lock.Unlock()
+}
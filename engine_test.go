@@ -1,34 +1,21 @@
 package trade
 
 import (
-	"errors"
+	"context"
 	"flag"
 	"reflect"
 	"testing"
 	"time"
 )
 
+// expect waits up to seconds for ch to deliver one of the expected replies,
+// using the public Engine.Expect so tests no longer spin their own
+// select/timeout loop.
 func (engine *Engine) expect(t *testing.T, seconds int, ch chan Reply, expected []IncomingMessageId) (Reply, error) {
-	for {
-		select {
-		case <-time.After(time.Duration(seconds) * time.Second):
-			return nil, errors.New("Timeout waiting")
-		case v := <-ch:
-			if v.code() == 0 {
-				t.Fatalf("don't know message '%v'", v)
-			}
-			for _, code := range expected {
-				if v.code() == code {
-					return v, nil
-				}
-			}
-			// wrong message received
-			t.Logf("received message '%v' of type '%v'\n",
-				v, reflect.ValueOf(v).Type())
-		}
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(seconds)*time.Second)
+	defer cancel()
 
-	return nil, nil
+	return engine.Expect(ctx, ch, expected...)
 }
 
 // private variable for mantaining engine reuse in test
@@ -0,0 +1,48 @@
+package trade
+
+import (
+	"time"
+)
+
+// This file ports IB API ExecutionFilter.java. Please preserve declaration order.
+
+// ExecutionFilter narrows a RequestExecutions call down to the executions a
+// caller is actually interested in. Any zero-valued field is unfiltered.
+type ExecutionFilter struct {
+	ClientId int64
+	AcctCode string
+	Time     time.Time
+	Symbol   string
+	SecType  string
+	Exchange string
+	Side     string
+}
+
+// matches reports whether an Execution of contract satisfies every
+// non-zero field of f. Symbol and SecType are checked against contract,
+// since Execution itself carries neither.
+func (f ExecutionFilter) matches(contract Contract, exec Execution) bool {
+	if f.ClientId != 0 && f.ClientId != exec.ClientId {
+		return false
+	}
+	if f.AcctCode != "" && f.AcctCode != exec.AccountCode {
+		return false
+	}
+	if !f.Time.IsZero() && exec.Time.Before(f.Time) {
+		return false
+	}
+	if f.Symbol != "" && f.Symbol != contract.Symbol {
+		return false
+	}
+	if f.SecType != "" && f.SecType != contract.SecurityType {
+		return false
+	}
+	if f.Exchange != "" && f.Exchange != exec.Exchange {
+		return false
+	}
+	if f.Side != "" && f.Side != exec.Side {
+		return false
+	}
+
+	return true
+}
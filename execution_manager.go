@@ -0,0 +1,239 @@
+package trade
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ExecutionManager collects and maintains the set of Executions visible to
+// an Engine. Execution itself carries no commission data, so a live
+// mCommissionReport is kept alongside the mExecutionData it belongs to (by
+// ExecId) rather than merged into it; Commission retrieves it. It is the
+// Execution-oriented counterpart to InstrumentManager.
+type ExecutionManager struct {
+	engine *Engine
+	filter ExecutionFilter
+
+	cancel context.CancelFunc
+
+	lock        sync.Mutex
+	executions  map[string]Execution
+	commissions map[string]CommissionReport
+	byOrder     map[int64][]string
+	subscribers []chan Execution
+}
+
+// NewExecutionManager requests every Execution matching filter and returns
+// an ExecutionManager that keeps receiving (and exposing) live executions
+// and commission reports thereafter.
+func NewExecutionManager(engine *Engine, filter ExecutionFilter) (*ExecutionManager, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m := &ExecutionManager{
+		engine:      engine,
+		filter:      filter,
+		cancel:      cancel,
+		executions:  map[string]Execution{},
+		commissions: map[string]CommissionReport{},
+		byOrder:     map[int64][]string{},
+	}
+
+	if err := m.request(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// request issues RequestExecutions against m's filter, waits for the
+// historical batch to complete, then keeps the subscription open for live
+// fills and commission reports for the lifetime of ctx. m.engine and
+// m.filter are snapshotted under lock so a concurrent Reconcile cannot
+// change them out from under a request already in flight.
+func (m *ExecutionManager) request(ctx context.Context) error {
+	m.lock.Lock()
+	engine := m.engine
+	filter := m.filter
+	m.lock.Unlock()
+
+	id := engine.NextRequestId()
+
+	ch := make(chan Reply)
+	engine.SubscribeContext(ctx, ch, id)
+
+	req := &RequestExecutions{Filter: filter}
+	req.SetId(id)
+	if err := engine.SendContext(ctx, req); err != nil {
+		return err
+	}
+
+	for {
+		r, err := engine.Expect(ctx, ch, mExecutionData, mExecutionDataEnd)
+		if err != nil {
+			return fmt.Errorf("trade: ExecutionManager: %s", err)
+		}
+
+		if end, ok := r.(*ExecutionDataEnd); ok && end.Id == id {
+			break
+		}
+		if data, ok := r.(*ExecutionData); ok {
+			m.add(data.Contract, data.Execution)
+		}
+	}
+
+	live := make(chan Reply)
+	engine.SubscribeQuery(ctx, Query{Match: func(r Reply) bool {
+		switch r.(type) {
+		case *ExecutionData, *CommissionReport:
+			return true
+		}
+		return false
+	}}, live)
+
+	go m.receive(ctx, live)
+
+	return nil
+}
+
+func (m *ExecutionManager) receive(ctx context.Context, ch chan Reply) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case r := <-ch:
+			switch v := r.(type) {
+			case *ExecutionData:
+				m.add(v.Contract, v.Execution)
+			case *CommissionReport:
+				m.applyCommission(v)
+			}
+		}
+	}
+}
+
+// add records exec if it matches m's filter and has not been recorded
+// already. The filter read, the dedup check and the map writes share a
+// single critical section so a concurrent Reconcile call cannot change the
+// filter mid-check, and so Reconcile's inclusive re-request of the boundary
+// Execution (see ExecutionFilter.matches) is not double-counted.
+func (m *ExecutionManager) add(contract Contract, exec Execution) {
+	m.lock.Lock()
+	_, seen := m.executions[exec.ExecId]
+	matched := !seen && m.filter.matches(contract, exec)
+	if matched {
+		m.executions[exec.ExecId] = exec
+		m.byOrder[exec.OrderId] = append(m.byOrder[exec.OrderId], exec.ExecId)
+	}
+	m.lock.Unlock()
+
+	if matched {
+		m.publish(exec)
+	}
+}
+
+// applyCommission records report against its Execution's ExecId and
+// republishes the Execution as a completion signal: subscribers already
+// holding that Execution know its commission is now available via
+// Commission, even though the republished Execution value is unchanged. A
+// report that arrives before its Execution is recorded but not published,
+// as IB never sends one without the other following shortly after.
+func (m *ExecutionManager) applyCommission(report *CommissionReport) {
+	m.lock.Lock()
+	m.commissions[report.ExecId] = *report
+	exec, ok := m.executions[report.ExecId]
+	m.lock.Unlock()
+
+	if ok {
+		m.publish(exec)
+	}
+}
+
+// Commission returns the CommissionReport received for execId, if any.
+func (m *ExecutionManager) Commission(execId string) (CommissionReport, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	c, ok := m.commissions[execId]
+	return c, ok
+}
+
+func (m *ExecutionManager) publish(exec Execution) {
+	m.lock.Lock()
+	subs := append([]chan Execution{}, m.subscribers...)
+	m.lock.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- exec:
+		default:
+		}
+	}
+}
+
+// Executions returns every Execution seen so far, in no particular order.
+func (m *ExecutionManager) Executions() []Execution {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	all := make([]Execution, 0, len(m.executions))
+	for _, exec := range m.executions {
+		all = append(all, exec)
+	}
+	return all
+}
+
+// ByOrderId returns every Execution seen for the given local order id.
+func (m *ExecutionManager) ByOrderId(id int64) []Execution {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ids := m.byOrder[id]
+	execs := make([]Execution, 0, len(ids))
+	for _, execId := range ids {
+		execs = append(execs, m.executions[execId])
+	}
+	return execs
+}
+
+// Subscribe registers ch to receive each Execution as it is added or
+// updated by a commission report. Sends do not block: an Execution that ch
+// is not ready to receive is dropped for that subscriber.
+func (m *ExecutionManager) Subscribe(ch chan Execution) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.subscribers = append(m.subscribers, ch)
+}
+
+// Reconcile re-issues RequestExecutions using the timestamp of the last
+// Execution seen, so a caller can recover any fills missed while the Engine
+// was disconnected without re-requesting the full execution history.
+func (m *ExecutionManager) Reconcile(engine *Engine) error {
+	m.lock.Lock()
+	m.cancel()
+
+	filter := m.filter
+	for _, exec := range m.executions {
+		if exec.Time.After(filter.Time) {
+			filter.Time = exec.Time
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.engine = engine
+	m.filter = filter
+	m.cancel = cancel
+	m.lock.Unlock()
+
+	return m.request(ctx)
+}
+
+// Close stops receiving live executions and commission reports.
+func (m *ExecutionManager) Close() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.cancel()
+}
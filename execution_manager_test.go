@@ -0,0 +1,89 @@
+package trade
+
+import (
+	"testing"
+	"time"
+)
+
+// TestExecutionManagerCommission checks a CommissionReport is recorded
+// against its Execution's ExecId without overwriting the Execution itself,
+// and is retrievable via Commission.
+func TestExecutionManagerCommission(t *testing.T) {
+	m := &ExecutionManager{
+		executions:  map[string]Execution{},
+		commissions: map[string]CommissionReport{},
+		byOrder:     map[int64][]string{},
+	}
+
+	exec := Execution{OrderId: 1, ExecId: "exec.1", Shares: 100, Price: 0.75}
+	m.add(Contract{Symbol: "AUD", SecurityType: "CASH"}, exec)
+
+	if _, ok := m.Commission("exec.1"); ok {
+		t.Fatal("expected no commission before a report arrives")
+	}
+
+	m.applyCommission(&CommissionReport{ExecId: "exec.1", Commission: 2.5, Currency: "USD"})
+
+	got, ok := m.Commission("exec.1")
+	if !ok {
+		t.Fatal("expected a commission report after applyCommission")
+	}
+	if got.Commission != 2.5 || got.Currency != "USD" {
+		t.Fatalf("unexpected commission report: %+v", got)
+	}
+
+	if stored := m.executions["exec.1"]; stored != exec {
+		t.Fatalf("applyCommission must not alter the stored Execution, got %+v", stored)
+	}
+}
+
+// TestExecutionManagerAddDedups checks a repeated ExecId (as Reconcile's
+// inclusive re-request is expected to produce at the boundary) is not
+// appended to byOrder or republished a second time.
+func TestExecutionManagerAddDedups(t *testing.T) {
+	m := &ExecutionManager{
+		executions:  map[string]Execution{},
+		commissions: map[string]CommissionReport{},
+		byOrder:     map[int64][]string{},
+	}
+
+	ch := make(chan Execution, 2)
+	m.Subscribe(ch)
+
+	contract := Contract{Symbol: "AUD", SecurityType: "CASH"}
+	exec := Execution{OrderId: 1, ExecId: "exec.1", Shares: 100, Price: 0.75}
+	m.add(contract, exec)
+	m.add(contract, exec)
+
+	if got := m.ByOrderId(1); len(got) != 1 {
+		t.Fatalf("expected exec.1 to be recorded once, got %v", got)
+	}
+	if len(ch) != 1 {
+		t.Fatalf("expected the duplicate add to not republish, got %d queued", len(ch))
+	}
+}
+
+func TestExecutionManager(t *testing.T) {
+	engine := NewTestEngine(t)
+
+	defer engine.ConditionalStop(t)
+
+	m, err := NewExecutionManager(engine, ExecutionFilter{ClientId: engine.ClientId()})
+	if err != nil {
+		t.Fatalf("error creating manager: %s", err)
+	}
+
+	defer m.Close()
+
+	ch := make(chan Execution, 1)
+	m.Subscribe(ch)
+
+	select {
+	case exec := <-ch:
+		if len(m.ByOrderId(exec.OrderId)) == 0 {
+			t.Fatalf("execution %s not indexed by order id", exec.ExecId)
+		}
+	case <-time.After(5 * time.Second):
+		t.Log("no live execution received within 5s; nothing to reconcile against")
+	}
+}
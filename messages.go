@@ -0,0 +1,175 @@
+package trade
+
+// PlaceOrder requests that TWS place Contract/Action/OrderType/TotalQty (and,
+// for a "LMT" OrderType, LimitPrice). OrderId is both the local order id and
+// the request id Replies for this order come back tagged with.
+type PlaceOrder struct {
+	OrderId    int64
+	Contract   Contract
+	Action     string
+	OrderType  string
+	TotalQty   int64
+	LimitPrice float64
+}
+
+// SetId sets OrderId, the id both this order and its Replies are tracked by.
+func (r *PlaceOrder) SetId(id int64) {
+	r.OrderId = id
+}
+
+// Cancel returns the CancelOrder that withdraws this order.
+func (r *PlaceOrder) Cancel() Request {
+	return &CancelOrder{OrderId: r.OrderId}
+}
+
+// CancelOrder requests that TWS cancel the working order OrderId.
+type CancelOrder struct {
+	OrderId int64
+}
+
+// SetId sets OrderId.
+func (r *CancelOrder) SetId(id int64) {
+	r.OrderId = id
+}
+
+// RequestMarketData subscribes to streaming TickPrice/TickSize Replies for
+// Contract.
+type RequestMarketData struct {
+	id       int64
+	Contract Contract
+}
+
+// SetId sets the request id TickPrice/TickSize Replies come back tagged
+// with.
+func (r *RequestMarketData) SetId(id int64) {
+	r.id = id
+}
+
+// Cancel returns the CancelMarketData that ends this subscription.
+func (r *RequestMarketData) Cancel() Request {
+	return &CancelMarketData{Id: r.id}
+}
+
+// CancelMarketData cancels a previous RequestMarketData.
+type CancelMarketData struct {
+	Id int64
+}
+
+// SetId sets Id, the request id of the RequestMarketData being cancelled.
+func (r *CancelMarketData) SetId(id int64) {
+	r.Id = id
+}
+
+// RequestContractData requests the ContractData TWS holds for Contract.
+type RequestContractData struct {
+	id       int64
+	Contract Contract
+}
+
+// SetId sets the request id ContractData/ContractDataEnd Replies come back
+// tagged with.
+func (r *RequestContractData) SetId(id int64) {
+	r.id = id
+}
+
+// RequestOpenOrders requests an OpenOrder Reply for every order still
+// working at TWS for this client, followed by OpenOrderEnd.
+type RequestOpenOrders struct {
+	id int64
+}
+
+// SetId sets the request id OpenOrder/OpenOrderEnd Replies come back tagged
+// with.
+func (r *RequestOpenOrders) SetId(id int64) {
+	r.id = id
+}
+
+// RequestExecutions requests every Execution matching Filter, delivered as
+// ExecutionData Replies followed by ExecutionDataEnd.
+type RequestExecutions struct {
+	id     int64
+	Filter ExecutionFilter
+}
+
+// SetId sets the request id ExecutionData/ExecutionDataEnd Replies come back
+// tagged with.
+func (r *RequestExecutions) SetId(id int64) {
+	r.id = id
+}
+
+// OrderStatus reports a change in the working state of order Id.
+type OrderStatus struct {
+	Id            int64
+	PermId        int64
+	Status        string
+	Filled        int64
+	AvgFillPrice  float64
+	LastFillPrice float64
+}
+
+func (r *OrderStatus) code() IncomingMessageId { return mOrderStatus }
+
+// OpenOrder describes one order still working at TWS, in reply to
+// RequestOpenOrders.
+type OpenOrder struct {
+	OrderId    int64
+	Contract   Contract
+	Action     string
+	OrderType  string
+	TotalQty   int64
+	LimitPrice float64
+}
+
+func (r *OpenOrder) code() IncomingMessageId { return mOpenOrder }
+
+// OpenOrderEnd marks the end of the OpenOrder batch a RequestOpenOrders
+// produced.
+type OpenOrderEnd struct {
+	Id int64
+}
+
+func (r *OpenOrderEnd) code() IncomingMessageId { return mOpenOrderEnd }
+
+// ExecutionData reports a fill for Contract.
+type ExecutionData struct {
+	Id        int64
+	Contract  Contract
+	Execution Execution
+}
+
+func (r *ExecutionData) code() IncomingMessageId { return mExecutionData }
+
+// ExecutionDataEnd marks the end of the ExecutionData batch a
+// RequestExecutions produced.
+type ExecutionDataEnd struct {
+	Id int64
+}
+
+func (r *ExecutionDataEnd) code() IncomingMessageId { return mExecutionDataEnd }
+
+// CommissionReport carries the commission TWS charged for the Execution
+// identified by ExecId, usually arriving shortly after its ExecutionData.
+type CommissionReport struct {
+	ExecId     string
+	Commission float64
+	Currency   string
+}
+
+func (r *CommissionReport) code() IncomingMessageId { return mCommissionReport }
+
+// ContractData describes the instrument TWS resolved a RequestContractData
+// to.
+type ContractData struct {
+	Id       int64
+	Contract Contract
+}
+
+func (r *ContractData) code() IncomingMessageId { return mContractData }
+
+// ContractDataEnd marks the end of the ContractData batch a
+// RequestContractData produced.
+type ContractDataEnd struct {
+	Id int64
+}
+
+func (r *ContractDataEnd) code() IncomingMessageId { return mContractDataEnd }
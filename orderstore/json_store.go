@@ -0,0 +1,134 @@
+package orderstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// JSONStore appends one JSON record per line to a file on disk. It is the
+// simplest Store implementation: no schema migrations, trivially
+// inspectable, but O(n) to rebuild its in-memory index on open.
+type JSONStore struct {
+	lock    sync.Mutex
+	file    *os.File
+	records []record
+}
+
+// NewJSONStore opens (creating if necessary) the file at path and replays
+// any records already in it.
+func NewJSONStore(path string) (*JSONStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &JSONStore{file: f}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			f.Close()
+			return nil, err
+		}
+		s.records = append(s.records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *JSONStore) append(r record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *JSONStore) SaveOrder(o Order) error {
+	return s.append(record{Kind: kindOrder, Order: &o})
+}
+
+func (s *JSONStore) SaveOrderStatus(o OrderStatus) error {
+	return s.append(record{Kind: kindOrderStatus, OrderStatus: &o})
+}
+
+func (s *JSONStore) SaveOpenOrder(o OpenOrder) error {
+	return s.append(record{Kind: kindOpenOrder, OpenOrder: &o})
+}
+
+func (s *JSONStore) SaveExecution(e Execution) error {
+	return s.append(record{Kind: kindExecution, Execution: &e})
+}
+
+func (s *JSONStore) SaveCommissionReport(c CommissionReport) error {
+	return s.append(record{Kind: kindCommissionReport, CommissionReport: &c})
+}
+
+func (s *JSONStore) Orders() ([]Order, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var orders []Order
+	for _, r := range s.records {
+		if r.Kind == kindOrder {
+			orders = append(orders, *r.Order)
+		}
+	}
+	return orders, nil
+}
+
+func (s *JSONStore) HighestOrderId() (int64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var highest int64
+	for _, r := range s.records {
+		if r.Kind == kindOrder && r.Order.OrderId > highest {
+			highest = r.Order.OrderId
+		}
+	}
+	return highest, nil
+}
+
+func (s *JSONStore) UnresolvedExecIds() ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	resolved := map[string]bool{}
+	for _, r := range s.records {
+		if r.Kind == kindCommissionReport {
+			resolved[r.CommissionReport.ExecId] = true
+		}
+	}
+
+	var unresolved []string
+	for _, r := range s.records {
+		if r.Kind == kindExecution && !resolved[r.Execution.ExecId] {
+			unresolved = append(unresolved, r.Execution.ExecId)
+		}
+	}
+	return unresolved, nil
+}
+
+func (s *JSONStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.file.Close()
+}
@@ -0,0 +1,71 @@
+package orderstore
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestJSONStoreRoundTrip verifies that records saved before a (simulated)
+// crash are still visible through Orders/HighestOrderId/UnresolvedExecIds
+// after the file is reopened, which is the property RecoverFromStore
+// depends on to reconcile a restarted Engine.
+func TestJSONStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.jsonl")
+
+	s, err := NewJSONStore(path)
+	if err != nil {
+		t.Fatalf("cannot create store: %s", err)
+	}
+
+	if err := s.SaveOrder(Order{OrderId: 1, Symbol: "AUD", SecType: "CASH", Exchange: "IDEALPRO", Action: "BUY", OrderType: "MKT", TotalQty: 20000}); err != nil {
+		t.Fatalf("cannot save order: %s", err)
+	}
+	if err := s.SaveOrder(Order{OrderId: 2, Symbol: "AAPL", SecType: "STK", Exchange: "SMART", Action: "SELL", OrderType: "LMT", TotalQty: 100, LimitPrice: 150}); err != nil {
+		t.Fatalf("cannot save order: %s", err)
+	}
+	if err := s.SaveExecution(Execution{OrderId: 1, ExecId: "exec.1", Shares: 20000, Price: 0.75, CumQty: 20000, AveragePrice: 0.75}); err != nil {
+		t.Fatalf("cannot save execution: %s", err)
+	}
+	if err := s.SaveExecution(Execution{OrderId: 2, ExecId: "exec.2", Shares: 100, Price: 150, CumQty: 100, AveragePrice: 150}); err != nil {
+		t.Fatalf("cannot save execution: %s", err)
+	}
+	if err := s.SaveCommissionReport(CommissionReport{ExecId: "exec.1", Commission: 2.5, Currency: "USD"}); err != nil {
+		t.Fatalf("cannot save commission report: %s", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("cannot close store: %s", err)
+	}
+
+	// Reopen, as a restarted process would, and check every record is
+	// still there.
+	s, err = NewJSONStore(path)
+	if err != nil {
+		t.Fatalf("cannot reopen store: %s", err)
+	}
+	defer s.Close()
+
+	orders, err := s.Orders()
+	if err != nil {
+		t.Fatalf("cannot list orders: %s", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("expected 2 orders after reopen, got %d", len(orders))
+	}
+
+	highest, err := s.HighestOrderId()
+	if err != nil {
+		t.Fatalf("cannot compute highest order id: %s", err)
+	}
+	if highest != 2 {
+		t.Fatalf("expected highest order id 2, got %d", highest)
+	}
+
+	unresolved, err := s.UnresolvedExecIds()
+	if err != nil {
+		t.Fatalf("cannot list unresolved exec ids: %s", err)
+	}
+	if len(unresolved) != 1 || unresolved[0] != "exec.2" {
+		t.Fatalf("expected only exec.2 unresolved, got %v", unresolved)
+	}
+}
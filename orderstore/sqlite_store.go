@@ -0,0 +1,167 @@
+//go:build sqlite
+// +build sqlite
+
+package orderstore
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteStore persists records to a single SQLite table, keyed by an
+// auto-incrementing rowid. It is built behind the "sqlite" tag so the
+// cgo-based driver is only pulled in by callers that want it; JSONStore has
+// no such dependency.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) the database at path.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS orderstore_record (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		payload TEXT NOT NULL
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) insert(kind string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT INTO orderstore_record (kind, payload) VALUES (?, ?)`, kind, string(data))
+	return err
+}
+
+func (s *SQLiteStore) SaveOrder(o Order) error {
+	return s.insert(kindOrder, o)
+}
+
+func (s *SQLiteStore) SaveOrderStatus(o OrderStatus) error {
+	return s.insert(kindOrderStatus, o)
+}
+
+func (s *SQLiteStore) SaveOpenOrder(o OpenOrder) error {
+	return s.insert(kindOpenOrder, o)
+}
+
+func (s *SQLiteStore) SaveExecution(e Execution) error {
+	return s.insert(kindExecution, e)
+}
+
+func (s *SQLiteStore) SaveCommissionReport(c CommissionReport) error {
+	return s.insert(kindCommissionReport, c)
+}
+
+func (s *SQLiteStore) Orders() ([]Order, error) {
+	rows, err := s.db.Query(`SELECT payload FROM orderstore_record WHERE kind = ?`, kindOrder)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []Order
+	for rows.Next() {
+		var payload string
+		if err := rows.Scan(&payload); err != nil {
+			return nil, err
+		}
+		var o Order
+		if err := json.Unmarshal([]byte(payload), &o); err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+	}
+	return orders, rows.Err()
+}
+
+func (s *SQLiteStore) HighestOrderId() (int64, error) {
+	rows, err := s.db.Query(`SELECT payload FROM orderstore_record WHERE kind = ?`, kindOrder)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var highest int64
+	for rows.Next() {
+		var payload string
+		if err := rows.Scan(&payload); err != nil {
+			return 0, err
+		}
+		var o Order
+		if err := json.Unmarshal([]byte(payload), &o); err != nil {
+			return 0, err
+		}
+		if o.OrderId > highest {
+			highest = o.OrderId
+		}
+	}
+	return highest, rows.Err()
+}
+
+func (s *SQLiteStore) UnresolvedExecIds() ([]string, error) {
+	resolved := map[string]bool{}
+
+	rows, err := s.db.Query(`SELECT payload FROM orderstore_record WHERE kind = ?`, kindCommissionReport)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var payload string
+		if err := rows.Scan(&payload); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		var c CommissionReport
+		if err := json.Unmarshal([]byte(payload), &c); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		resolved[c.ExecId] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	execRows, err := s.db.Query(`SELECT payload FROM orderstore_record WHERE kind = ?`, kindExecution)
+	if err != nil {
+		return nil, err
+	}
+	defer execRows.Close()
+
+	var unresolved []string
+	for execRows.Next() {
+		var payload string
+		if err := execRows.Scan(&payload); err != nil {
+			return nil, err
+		}
+		var e Execution
+		if err := json.Unmarshal([]byte(payload), &e); err != nil {
+			return nil, err
+		}
+		if !resolved[e.ExecId] {
+			unresolved = append(unresolved, e.ExecId)
+		}
+	}
+	return unresolved, execRows.Err()
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
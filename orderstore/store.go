@@ -0,0 +1,109 @@
+// Package orderstore persists the outbound orders and inbound order/
+// execution replies an Engine sees, so a process restart can reconcile
+// against what TWS already knows rather than starting from a blank slate.
+//
+// The types here are intentionally decoupled from the trade package's
+// concrete PlaceOrder/OrderStatus/etc. structs: trade imports orderstore to
+// wire a Store into an Engine, so orderstore cannot import trade back
+// without an import cycle. Callers convert at the boundary.
+package orderstore
+
+import (
+	"time"
+)
+
+// Store is implemented by every orderstore backend. Save methods are called
+// synchronously as the corresponding message is sent or received, so
+// implementations should not block for long.
+type Store interface {
+	SaveOrder(o Order) error
+	SaveOrderStatus(o OrderStatus) error
+	SaveOpenOrder(o OpenOrder) error
+	SaveExecution(e Execution) error
+	SaveCommissionReport(c CommissionReport) error
+
+	// Orders returns every Order persisted so far, for reconciliation
+	// against a fresh RequestOpenOrders after a restart.
+	Orders() ([]Order, error)
+
+	// HighestOrderId returns the highest local OrderId seen across every
+	// persisted Order, or 0 if none have been saved. This is the engine's
+	// own per-session request/order-id sequence, not an IB-assigned PermId,
+	// which lives in a different (and much larger) id space.
+	HighestOrderId() (int64, error)
+
+	// UnresolvedExecIds returns the ExecId of every Execution persisted
+	// without a matching CommissionReport.
+	UnresolvedExecIds() ([]string, error)
+
+	Close() error
+}
+
+// Order is the persisted form of an outbound PlaceOrder.
+type Order struct {
+	OrderId    int64
+	Symbol     string
+	SecType    string
+	Exchange   string
+	Action     string
+	OrderType  string
+	TotalQty   int64
+	LimitPrice float64
+}
+
+// OrderStatus is the persisted form of an inbound OrderStatus reply.
+type OrderStatus struct {
+	OrderId       int64
+	PermId        int64
+	Status        string
+	Filled        int64
+	AvgFillPrice  float64
+	LastFillPrice float64
+}
+
+// OpenOrder is the persisted form of an inbound OpenOrder reply.
+type OpenOrder struct {
+	OrderId int64
+	Order   Order
+}
+
+// Execution is the persisted form of an inbound ExecutionData reply.
+type Execution struct {
+	OrderId      int64
+	ExecId       string
+	Time         time.Time
+	AccountCode  string
+	Exchange     string
+	Side         string
+	Shares       int64
+	Price        float64
+	CumQty       int64
+	AveragePrice float64
+}
+
+// CommissionReport is the persisted form of an inbound CommissionReport
+// reply.
+type CommissionReport struct {
+	ExecId     string
+	Commission float64
+	Currency   string
+}
+
+// record is the common envelope every backend persists a message under.
+type record struct {
+	Kind             string
+	Time             time.Time
+	Order            *Order            `json:",omitempty"`
+	OrderStatus      *OrderStatus      `json:",omitempty"`
+	OpenOrder        *OpenOrder        `json:",omitempty"`
+	Execution        *Execution        `json:",omitempty"`
+	CommissionReport *CommissionReport `json:",omitempty"`
+}
+
+const (
+	kindOrder            = "Order"
+	kindOrderStatus      = "OrderStatus"
+	kindOpenOrder        = "OpenOrder"
+	kindExecution        = "Execution"
+	kindCommissionReport = "CommissionReport"
+)
@@ -0,0 +1,131 @@
+// Package pubsub is a small, typed publish/subscribe layer modelled on
+// tendermint's libs/pubsub. A single dispatcher goroutine matches each
+// published event against every registered Query and forwards it to that
+// subscription's channel, so publishers never block on slow subscribers and
+// subscribers never need to know which message ids they care about ahead of
+// time.
+package pubsub
+
+import (
+	"context"
+	"log"
+	"reflect"
+)
+
+// Query selects which published events a subscription receives. An event
+// matches if its concrete type is present in Types (when Types is
+// non-empty) and, when Match is set, Match(event) also returns true. A zero
+// Query matches every event.
+type Query struct {
+	Types []reflect.Type
+	Match func(event interface{}) bool
+}
+
+func (q Query) matches(event interface{}) bool {
+	if len(q.Types) > 0 {
+		t := reflect.TypeOf(event)
+		found := false
+		for _, want := range q.Types {
+			if t == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if q.Match != nil {
+		return q.Match(event)
+	}
+
+	return true
+}
+
+type subscription struct {
+	query Query
+	out   chan interface{}
+}
+
+// publication is a request to deliver an event to the dispatcher goroutine.
+type publication struct {
+	event interface{}
+}
+
+// Bus is a single-dispatcher publish/subscribe registry. The zero value is
+// not usable; create one with NewBus.
+type Bus struct {
+	subscribe   chan subscription
+	unsubscribe chan chan interface{}
+	publish     chan publication
+	done        chan struct{}
+}
+
+// NewBus starts a Bus and its dispatcher goroutine. Call Stop to shut it
+// down.
+func NewBus() *Bus {
+	b := &Bus{
+		subscribe:   make(chan subscription),
+		unsubscribe: make(chan chan interface{}),
+		publish:     make(chan publication),
+		done:        make(chan struct{}),
+	}
+
+	go b.loop()
+	return b
+}
+
+func (b *Bus) loop() {
+	subs := map[chan interface{}]Query{}
+
+	for {
+		select {
+		case s := <-b.subscribe:
+			subs[s.out] = s.query
+		case ch := <-b.unsubscribe:
+			delete(subs, ch)
+		case p := <-b.publish:
+			for ch, q := range subs {
+				if !q.matches(p.event) {
+					continue
+				}
+				select {
+				case ch <- p.event:
+				default:
+					log.Printf("pubsub: dropping event for slow subscriber: %v", p.event)
+				}
+			}
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// Subscribe registers out to receive every published event matching query,
+// until ctx is done.
+func (b *Bus) Subscribe(ctx context.Context, query Query, out chan interface{}) {
+	b.subscribe <- subscription{query: query, out: out}
+
+	go func() {
+		<-ctx.Done()
+		select {
+		case b.unsubscribe <- out:
+		case <-b.done:
+		}
+	}()
+}
+
+// Publish delivers event to every matching subscription. It does not
+// block on slow subscribers.
+func (b *Bus) Publish(event interface{}) {
+	select {
+	case b.publish <- publication{event: event}:
+	case <-b.done:
+	}
+}
+
+// Stop shuts down the dispatcher goroutine.
+func (b *Bus) Stop() {
+	close(b.done)
+}
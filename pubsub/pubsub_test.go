@@ -0,0 +1,79 @@
+package pubsub
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSubscribeMatchesType(t *testing.T) {
+	b := NewBus()
+	defer b.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out := make(chan interface{}, 1)
+	b.Subscribe(ctx, Query{Types: []reflect.Type{reflect.TypeOf("")}}, out)
+
+	b.Publish(42)
+	b.Publish("hello")
+
+	select {
+	case event := <-out:
+		if event != "hello" {
+			t.Fatalf("expected \"hello\", got %v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for matching event")
+	}
+}
+
+func TestSubscribeMatchFunc(t *testing.T) {
+	b := NewBus()
+	defer b.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out := make(chan interface{}, 1)
+	b.Subscribe(ctx, Query{Match: func(event interface{}) bool {
+		n, ok := event.(int)
+		return ok && n > 10
+	}}, out)
+
+	b.Publish(1)
+	b.Publish(20)
+
+	select {
+	case event := <-out:
+		if event != 20 {
+			t.Fatalf("expected 20, got %v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for matching event")
+	}
+}
+
+func TestUnsubscribeOnContextDone(t *testing.T) {
+	b := NewBus()
+	defer b.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	out := make(chan interface{}, 1)
+	b.Subscribe(ctx, Query{}, out)
+	cancel()
+
+	// give the dispatcher a moment to process the unsubscribe
+	time.Sleep(50 * time.Millisecond)
+
+	b.Publish("should not be delivered")
+
+	select {
+	case event := <-out:
+		t.Fatalf("expected no event after unsubscribe, got %v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
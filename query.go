@@ -0,0 +1,109 @@
+package trade
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/benalexau/go.trade/pubsub"
+)
+
+// UnsolicitedId is the request id TWS attaches to replies that were not
+// triggered by one of our own requests, eg an execution or order status
+// update pushed out as it happens rather than in answer to
+// RequestExecutions/RequestOpenOrders. SubscribeQuery feeds its Bus from
+// this id so that query-based subscribers see the same unsolicited traffic
+// the rest of the Engine already does.
+const UnsolicitedId int64 = -1
+
+// buses holds the lazily-created pubsub.Bus for each Engine.
+var buses = newEngineRegistry[*pubsub.Bus]()
+
+// bus returns e's pubsub.Bus, creating it (and the goroutine that feeds it
+// from e's unsolicited reply stream) on first use. The feeder goroutine, the
+// Bus and the registry entry are all torn down once e reaches a terminal
+// EngineState, so neither leaks for the lifetime of the process.
+func (e *Engine) bus() *pubsub.Bus {
+	if b, ok := buses.get(e); ok {
+		return b
+	}
+
+	b := pubsub.NewBus()
+	buses.set(e, b)
+
+	raw := make(chan Reply)
+	e.Subscribe(raw, UnsolicitedId)
+
+	states := make(chan EngineState, 1)
+	e.SubscribeState(states)
+
+	go func() {
+		for {
+			select {
+			case r, ok := <-raw:
+				if !ok {
+					return
+				}
+				b.Publish(r)
+			case <-states:
+				e.Unsubscribe(raw, UnsolicitedId)
+				b.Stop()
+				buses.delete(e)
+				return
+			}
+		}
+	}()
+
+	return b
+}
+
+// Query selects which Replies a SubscribeQuery subscription receives, eg
+//
+//	Query{Types: []reflect.Type{reflect.TypeOf(&ExecutionData{})}}
+//
+// matches every unsolicited ExecutionData reply. A zero Query matches every
+// unsolicited Reply the Engine demultiplexes.
+type Query struct {
+	Types []reflect.Type
+	Match func(r Reply) bool
+}
+
+func (q Query) toPubsub() pubsub.Query {
+	return pubsub.Query{
+		Types: q.Types,
+		Match: func(event interface{}) bool {
+			if q.Match == nil {
+				return true
+			}
+			return q.Match(event.(Reply))
+		},
+	}
+}
+
+// SubscribeQuery registers out to receive every unsolicited Reply the
+// Engine demultiplexes that matches query, regardless of the request id it
+// was sent against. The subscription is removed once ctx is done. Unlike
+// Subscribe, a single SubscribeQuery can follow a whole class of Replies
+// (eg "every ExecutionData for account X") without the caller tracking
+// individual request ids.
+func (e *Engine) SubscribeQuery(ctx context.Context, query Query, out chan Reply) {
+	forward := make(chan interface{})
+	e.bus().Subscribe(ctx, query.toPubsub(), forward)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-forward:
+				if !ok {
+					return
+				}
+				select {
+				case out <- event.(Reply):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,245 @@
+package trade
+
+import (
+	"context"
+	"log"
+
+	"github.com/benalexau/go.trade/orderstore"
+)
+
+// EngineOption configures optional Engine behaviour at construction time,
+// eg NewEngine(WithStore(s)).
+type EngineOption func(*Engine)
+
+// WithStore persists every outbound PlaceOrder and inbound OrderStatus,
+// OpenOrder, ExecutionData and CommissionReport to s, so a restarted Engine
+// can reconcile against what TWS already knows rather than starting blind.
+func WithStore(s orderstore.Store) EngineOption {
+	return func(e *Engine) {
+		AttachStore(e, s)
+	}
+}
+
+// stores holds the Store (if any) attached to each Engine, so Send can
+// persist outbound PlaceOrder requests as they are made. Entries are
+// removed once the Engine reaches a terminal EngineState.
+var stores = newEngineRegistry[orderstore.Store]()
+
+// AttachStore wires s into e until e reaches a terminal EngineState,
+// persisting every order/execution message e sends or receives until then.
+// NewEngine calls this for every WithStore option it is passed; callers
+// normally do not need to call it directly.
+func AttachStore(e *Engine, s orderstore.Store) {
+	stores.set(e, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	states := make(chan EngineState, 1)
+	e.SubscribeState(states)
+	go func() {
+		<-states
+		cancel()
+		stores.delete(e)
+	}()
+
+	ch := make(chan Reply)
+	e.SubscribeQuery(ctx, Query{Match: func(r Reply) bool {
+		switch r.(type) {
+		case *OrderStatus, *OpenOrder, *ExecutionData, *CommissionReport:
+			return true
+		}
+		return false
+	}}, ch)
+
+	// ctx is cancelled the moment e reaches a terminal EngineState, so this
+	// goroutine and the one SubscribeQuery started to feed ch both exit
+	// rather than leaking across an engine restart.
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case r := <-ch:
+				if err := saveReply(s, r); err != nil {
+					log.Printf("trade: orderstore: %s", err)
+				}
+			}
+		}
+	}()
+}
+
+// notifyStoreOfSend persists r to e's attached Store, if any, when r is an
+// outbound PlaceOrder. Send calls this (via SendContext) for every Request
+// it sends; it is a no-op for Engines with no store attached.
+func notifyStoreOfSend(e *Engine, r Request) {
+	s, ok := stores.get(e)
+	if !ok {
+		return
+	}
+
+	if po, ok := r.(*PlaceOrder); ok {
+		if err := s.SaveOrder(orderFromPlaceOrder(po)); err != nil {
+			log.Printf("trade: orderstore: %s", err)
+		}
+	}
+}
+
+// orderFromPlaceOrder converts a trade PlaceOrder request into the plain
+// value type orderstore persists. This package imports orderstore, so
+// orderstore cannot import this package's concrete types without an import
+// cycle; every Save call converts at this boundary instead.
+func orderFromPlaceOrder(po *PlaceOrder) orderstore.Order {
+	return orderstore.Order{
+		OrderId:    po.OrderId,
+		Symbol:     po.Contract.Symbol,
+		SecType:    po.Contract.SecurityType,
+		Exchange:   po.Contract.Exchange,
+		Action:     po.Action,
+		OrderType:  po.OrderType,
+		TotalQty:   po.TotalQty,
+		LimitPrice: po.LimitPrice,
+	}
+}
+
+func saveReply(s orderstore.Store, r Reply) error {
+	switch v := r.(type) {
+	case *OrderStatus:
+		return s.SaveOrderStatus(orderstore.OrderStatus{
+			OrderId:       v.Id,
+			PermId:        v.PermId,
+			Status:        v.Status,
+			Filled:        v.Filled,
+			AvgFillPrice:  v.AvgFillPrice,
+			LastFillPrice: v.LastFillPrice,
+		})
+	case *OpenOrder:
+		return s.SaveOpenOrder(orderstore.OpenOrder{
+			OrderId: v.OrderId,
+			Order: orderstore.Order{
+				OrderId:    v.OrderId,
+				Symbol:     v.Contract.Symbol,
+				SecType:    v.Contract.SecurityType,
+				Exchange:   v.Contract.Exchange,
+				Action:     v.Action,
+				OrderType:  v.OrderType,
+				TotalQty:   v.TotalQty,
+				LimitPrice: v.LimitPrice,
+			},
+		})
+	case *ExecutionData:
+		e := v.Execution
+		return s.SaveExecution(orderstore.Execution{
+			OrderId:      e.OrderId,
+			ExecId:       e.ExecId,
+			Time:         e.Time,
+			AccountCode:  e.AccountCode,
+			Exchange:     e.Exchange,
+			Side:         e.Side,
+			Shares:       e.Shares,
+			Price:        e.Price,
+			CumQty:       e.CumQty,
+			AveragePrice: e.AveragePrice,
+		})
+	case *CommissionReport:
+		return s.SaveCommissionReport(orderstore.CommissionReport{
+			ExecId:     v.ExecId,
+			Commission: v.Commission,
+			Currency:   v.Currency,
+		})
+	}
+	return nil
+}
+
+// RecoverFromStore reissues RequestOpenOrders and reconciles the replies
+// against what s already has on disk: any persisted Order that TWS no
+// longer reports as open is logged, since it was filled or cancelled while
+// the Engine was not running to see it happen live. It also logs a warning
+// if e's next local id has not already advanced past the highest order id s
+// has on record, since that would mean a restarted Engine could reuse an id
+// a persisted order already claimed; HighestOrderId is the engine's own
+// per-session order-id sequence; it is not comparable to an IB-assigned
+// PermId, which lives in an entirely different (and much larger) id space,
+// so there is nothing for RecoverFromStore to busy-loop advancing past.
+// Finally, it logs every ExecId with no matching CommissionReport on disk,
+// so a caller knows which fills still need their commission to arrive
+// (typically by pointing a fresh ExecutionManager at e) before they can be
+// considered fully settled.
+func RecoverFromStore(e *Engine, s orderstore.Store) error {
+	highest, err := s.HighestOrderId()
+	if err != nil {
+		return err
+	}
+
+	id := e.NextRequestId()
+	if id <= highest {
+		log.Printf("trade: orderstore: next request id %d has not cleared the highest persisted order id %d", id, highest)
+	}
+
+	stored, err := s.Orders()
+	if err != nil {
+		return err
+	}
+	pending := make(map[int64]orderstore.Order, len(stored))
+	for _, o := range stored {
+		pending[o.OrderId] = o
+	}
+
+	ch := make(chan Reply)
+	e.Subscribe(ch, id)
+	defer e.Unsubscribe(ch, id)
+
+	req := &RequestOpenOrders{}
+	req.SetId(id)
+	if err := e.Send(req); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for {
+		r, err := e.Expect(ctx, ch, mOpenOrder, mOpenOrderEnd)
+		if err != nil {
+			return err
+		}
+		if _, ok := r.(*OpenOrderEnd); ok {
+			for orderId := range pending {
+				log.Printf("trade: orderstore: persisted order %d is no longer open at TWS", orderId)
+			}
+			return logUnresolvedExecIds(s)
+		}
+		if open, ok := r.(*OpenOrder); ok {
+			delete(pending, open.OrderId)
+			if err := s.SaveOpenOrder(orderstore.OpenOrder{
+				OrderId: open.OrderId,
+				Order: orderstore.Order{
+					OrderId:    open.OrderId,
+					Symbol:     open.Contract.Symbol,
+					SecType:    open.Contract.SecurityType,
+					Exchange:   open.Contract.Exchange,
+					Action:     open.Action,
+					OrderType:  open.OrderType,
+					TotalQty:   open.TotalQty,
+					LimitPrice: open.LimitPrice,
+				},
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// logUnresolvedExecIds warns about every Execution s has persisted without a
+// matching CommissionReport, so whoever restarted e knows which fills from
+// before the restart are not yet settled.
+func logUnresolvedExecIds(s orderstore.Store) error {
+	unresolved, err := s.UnresolvedExecIds()
+	if err != nil {
+		return err
+	}
+
+	for _, execId := range unresolved {
+		log.Printf("trade: orderstore: execution %s has no persisted commission report", execId)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package trade
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benalexau/go.trade/orderstore"
+)
+
+// TestRecoverFromStore sends a market order through an Engine attached to a
+// JSONStore, then reopens that store (simulating a crash and restart) and
+// checks RecoverFromStore reconciles against it without error, surfacing any
+// fill that arrived before the crash but never got its commission report.
+func TestRecoverFromStore(t *testing.T) {
+	engine := NewTestEngine(t)
+
+	defer engine.ConditionalStop(t)
+
+	path := filepath.Join(t.TempDir(), "orders.jsonl")
+	store, err := orderstore.NewJSONStore(path)
+	if err != nil {
+		t.Fatalf("cannot create store: %s", err)
+	}
+
+	AttachStore(engine, store)
+
+	req := &PlaceOrder{
+		Contract: Contract{
+			Symbol:       "AUD",
+			SecurityType: "CASH",
+			Exchange:     "IDEALPRO",
+			Currency:     "USD",
+		},
+		Action:    "BUY",
+		OrderType: "MKT",
+		TotalQty:  20000,
+	}
+
+	id := engine.NextRequestId()
+	req.SetId(id)
+	if err := engine.Send(req); err != nil {
+		t.Fatalf("cannot send order: %s", err)
+	}
+
+	// AttachStore persists asynchronously as replies arrive; give it a
+	// moment before treating the store as representing "what TWS knew at
+	// crash time".
+	time.Sleep(2 * time.Second)
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("cannot close store: %s", err)
+	}
+
+	restarted, err := orderstore.NewJSONStore(path)
+	if err != nil {
+		t.Fatalf("cannot reopen store: %s", err)
+	}
+	defer restarted.Close()
+
+	if err := RecoverFromStore(engine, restarted); err != nil {
+		t.Fatalf("cannot recover from store: %s", err)
+	}
+
+	orders, err := restarted.Orders()
+	if err != nil {
+		t.Fatalf("cannot list orders: %s", err)
+	}
+	if len(orders) == 0 {
+		t.Fatal("expected the placed order to be persisted")
+	}
+}